@@ -0,0 +1,14 @@
+// Command nanobanana is the CLI entry point.
+package main
+
+import (
+	"os"
+
+	"github.com/lyalindotcom/nano-banana-cli/internal/cli"
+)
+
+func main() {
+	if err := cli.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lyalindotcom/nano-banana-cli/internal/gemini"
+)
+
+func TestLoadBatchManifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.jsonl")
+	writeFile(t, path, `{"id": "job-1", "prompt": "a sunset", "output": "out/job-1.png"}
+
+{"id": "job-2", "prompt": "add sunglasses", "input": "face.png", "output": "out/job-2.png", "count": 2}
+`)
+
+	jobs, err := loadBatchManifest(path)
+	if err != nil {
+		t.Fatalf("loadBatchManifest returned error: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("len(jobs) = %d, want 2", len(jobs))
+	}
+	if jobs[0].ID != "job-1" || jobs[0].Prompt != "a sunset" || jobs[0].Output != "out/job-1.png" {
+		t.Errorf("jobs[0] = %+v", jobs[0])
+	}
+	if jobs[1].ID != "job-2" || jobs[1].Input != "face.png" || jobs[1].Count != 2 {
+		t.Errorf("jobs[1] = %+v", jobs[1])
+	}
+}
+
+func TestLoadBatchManifestMissingID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.jsonl")
+	writeFile(t, path, `{"prompt": "no id here"}`)
+
+	if _, err := loadBatchManifest(path); err == nil {
+		t.Error("loadBatchManifest with no \"id\" field = nil error, want error")
+	}
+}
+
+func TestLoadBatchManifestInvalidJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.jsonl")
+	writeFile(t, path, `not json`)
+
+	if _, err := loadBatchManifest(path); err == nil {
+		t.Error("loadBatchManifest with invalid JSON = nil error, want error")
+	}
+}
+
+func TestLoadBatchManifestMissingFile(t *testing.T) {
+	if _, err := loadBatchManifest(filepath.Join(t.TempDir(), "missing.jsonl")); err == nil {
+		t.Error("loadBatchManifest with missing file = nil error, want error")
+	}
+}
+
+func TestLoadBatchStateEmptyPath(t *testing.T) {
+	state, err := loadBatchState("")
+	if err != nil {
+		t.Fatalf("loadBatchState(\"\") returned error: %v", err)
+	}
+	if len(state) != 0 {
+		t.Errorf("loadBatchState(\"\") = %v, want empty", state)
+	}
+}
+
+func TestLoadBatchStateMissingFile(t *testing.T) {
+	state, err := loadBatchState(filepath.Join(t.TempDir(), "missing.state"))
+	if err != nil {
+		t.Fatalf("loadBatchState with missing file returned error: %v", err)
+	}
+	if len(state) != 0 {
+		t.Errorf("loadBatchState with missing file = %v, want empty", state)
+	}
+}
+
+func TestLoadBatchState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.state")
+	writeFile(t, path, "job-1\n\njob-2\n")
+
+	state, err := loadBatchState(path)
+	if err != nil {
+		t.Fatalf("loadBatchState returned error: %v", err)
+	}
+	if !state["job-1"] || !state["job-2"] || len(state) != 2 {
+		t.Errorf("loadBatchState = %v, want {job-1, job-2}", state)
+	}
+}
+
+func TestIsRetryableBatchError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"quota exceeded", &gemini.GeminiError{Code: gemini.ErrQuotaExceeded}, true},
+		{"server error", &gemini.GeminiError{Code: gemini.ErrServerError}, true},
+		{"invalid request", &gemini.GeminiError{Code: gemini.ErrInvalidRequest}, false},
+		{"invalid api key", &gemini.GeminiError{Code: gemini.ErrInvalidAPIKey}, false},
+		{"non-gemini error", errNotGemini{}, false},
+	}
+	for _, tc := range cases {
+		if got := isRetryableBatchError(tc.err); got != tc.want {
+			t.Errorf("%s: isRetryableBatchError() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+type errNotGemini struct{}
+
+func (errNotGemini) Error() string { return "boom" }
+
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("failed to write test file %s: %v", path, err)
+	}
+}
@@ -2,28 +2,46 @@ package cli
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	_ "golang.org/x/image/webp"
+
 	"github.com/lyalindotcom/nano-banana-cli/internal/gemini"
+	"github.com/lyalindotcom/nano-banana-cli/internal/imaging"
 	"github.com/lyalindotcom/nano-banana-cli/internal/output"
+	"github.com/lyalindotcom/nano-banana-cli/internal/preview"
+	"github.com/lyalindotcom/nano-banana-cli/internal/storage"
 	"github.com/spf13/cobra"
 )
 
 var (
 	// Generate command flags
-	outputPath  string
-	inputPath   string
-	promptFile  string
-	count       int
-	aspectRatio string
-	resolution  string
-	noOverwrite bool
+	outputPath     string
+	inputPaths     []string
+	maskPath       string
+	promptFile     string
+	count          int
+	aspectRatio    string
+	resolution     string
+	noOverwrite    bool
+	previewMode    string
+	transform      string
+	thumbnailSizes string
+	safety         string
+	dryRunSafety   bool
 )
 
 var generateCmd = &cobra.Command{
@@ -51,6 +69,28 @@ ASPECT RATIOS:
 RESOLUTIONS (pro model only):
   1K (default), 2K, 4K
 
+PREVIEW:
+  Pass --preview (or set NANOBANANA_PREVIEW) to render the generated image
+  inline after saving: auto, kitty, iterm, sixel, or none. "auto" detects the
+  terminal from $TERM/$KITTY_WINDOW_ID/$TERM_PROGRAM. Previews are skipped
+  automatically when stdout isn't a terminal or --format json is set.
+
+TRANSFORM:
+  --transform applies a comma-separated pipeline to each generated image
+  before it's saved: resize=WxH[:fit|crop], format=ext, quality=N,
+  thumbnail=N. --thumbnail-sizes writes additional square thumbnail siblings
+  (out_256.png, out_512.png, ...) alongside the main output. PNG and JPEG
+  are encoded directly; WebP, AVIF, and HEIC are produced by shelling out
+  to ffmpeg.
+
+SAFETY:
+  --safety overrides the default blocking threshold per category, e.g.
+  harassment=block_none,hate=block_low_and_above. Categories: harassment,
+  hate, sexually_explicit, dangerous_content. Thresholds: block_none,
+  block_only_high, block_low_and_above, block_medium_and_above.
+  --dry-run-safety classifies the prompt without generating or saving an
+  image, for pre-screening prompts in bulk.
+
 EXAMPLES:
   # Generate a simple image
   nanobanana generate "a sunset over mountains" -o sunset.png
@@ -64,9 +104,25 @@ EXAMPLES:
   # Edit an existing image
   nanobanana generate "add sunglasses" -i face.png -o face-sunglasses.png
 
+  # Compositional edit from multiple reference images
+  nanobanana generate "place the subject from image 1 into the scene from image 2" \
+    -i subject.png -i scene.png -o composite.png
+
+  # Inpaint a masked region
+  nanobanana generate "replace the sky with a starry night" -i photo.png --mask sky-mask.png -o photo-edited.png
+
   # Generate multiple variations
   nanobanana generate "abstract art" -o art.png --count 4
 
+  # Resize, convert to webp, and write thumbnail siblings
+  nanobanana generate "product shot" -o shot.png --transform "resize=1024x1024:fit,format=webp,quality=85" --thumbnail-sizes 96,256,512
+
+  # Loosen safety thresholds for a deployment that needs them
+  nanobanana generate "a horror movie poster" -o poster.png --safety harassment=block_none,hate=block_low_and_above
+
+  # Pre-screen a prompt without generating an image
+  nanobanana generate "a risky prompt" --dry-run-safety --format json
+
   # Complex multi-line prompt from file
   nanobanana generate --prompt-file detailed-scene.txt -o scene.png
 
@@ -81,14 +137,18 @@ EXAMPLES:
 
 func init() {
 	generateCmd.Flags().StringVarP(&outputPath, "output", "o", "", "Output file path (required)")
-	generateCmd.Flags().StringVarP(&inputPath, "input", "i", "", "Input image for editing")
+	generateCmd.Flags().StringArrayVarP(&inputPaths, "input", "i", nil, "Input image for editing (repeatable for multi-image composition)")
+	generateCmd.Flags().StringVar(&maskPath, "mask", "", "Alpha-mask image marking the region of the first input to edit")
 	generateCmd.Flags().StringVarP(&promptFile, "prompt-file", "p", "", "Read prompt from file (supports multi-line)")
 	generateCmd.Flags().IntVarP(&count, "count", "c", 1, "Number of images to generate (1-10)")
 	generateCmd.Flags().StringVar(&aspectRatio, "aspect-ratio", "1:1", "Aspect ratio: 1:1, 16:9, 9:16, 4:3, 3:4, etc.")
 	generateCmd.Flags().StringVar(&resolution, "resolution", "", "Resolution: 1K, 2K, 4K (4K only with pro model)")
 	generateCmd.Flags().BoolVar(&noOverwrite, "no-overwrite", false, "Fail if output file exists")
-
-	generateCmd.MarkFlagRequired("output")
+	generateCmd.Flags().StringVar(&previewMode, "preview", "", "Render generated images inline in the terminal: auto, kitty, iterm, sixel, none")
+	generateCmd.Flags().StringVar(&transform, "transform", "", "Post-generation pipeline, e.g. resize=1024x1024:fit,format=webp,quality=85,thumbnail=256")
+	generateCmd.Flags().StringVar(&thumbnailSizes, "thumbnail-sizes", "", "Comma-separated square thumbnail sizes to write as siblings, e.g. 96,256,512")
+	generateCmd.Flags().StringVar(&safety, "safety", "", "Per-category safety thresholds, e.g. harassment=block_none,hate=block_low_and_above")
+	generateCmd.Flags().BoolVar(&dryRunSafety, "dry-run-safety", false, "Classify the prompt's safety ratings only; no image is generated or saved")
 }
 
 func runGenerate(cmd *cobra.Command, args []string) error {
@@ -129,9 +189,39 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid aspect ratio")
 	}
 
+	if outputPath == "" && !dryRunSafety {
+		f.Error("generate", "MISSING_OUTPUT", "Required flag \"output\" not set", "")
+		return fmt.Errorf("missing output path")
+	}
+
+	safetySettings, err := gemini.ParseSafetySettings(safety)
+	if err != nil {
+		f.Error("generate", "INVALID_SAFETY", err.Error(), "")
+		return err
+	}
+
+	transformSpec, err := imaging.Parse(transform)
+	if err != nil {
+		f.Error("generate", "INVALID_TRANSFORM", err.Error(), "")
+		return err
+	}
+	extraSizes, err := imaging.ParseThumbnailSizes(thumbnailSizes)
+	if err != nil {
+		f.Error("generate", "INVALID_THUMBNAIL_SIZES", err.Error(), "")
+		return err
+	}
+	transformSpec.ThumbnailSizes = append(transformSpec.ThumbnailSizes, extraSizes...)
+
+	ctx := context.Background()
+
 	// Check for existing file
-	if noOverwrite {
-		if _, err := os.Stat(outputPath); err == nil {
+	if noOverwrite && outputPath != "" {
+		exists, err := storage.Exists(ctx, outputPath)
+		if err != nil {
+			f.Error("generate", "STORAGE_ERROR", err.Error(), "")
+			return err
+		}
+		if exists {
 			f.Error("generate", "FILE_EXISTS",
 				fmt.Sprintf("Output file already exists: %s", outputPath),
 				"Use a different output path or remove --no-overwrite flag")
@@ -149,26 +239,55 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 
 	// Build config
 	config := &gemini.ImageConfig{
-		AspectRatio: aspectRatio,
-		Resolution:  resolution,
-		Count:       count,
+		AspectRatio:    aspectRatio,
+		Resolution:     resolution,
+		Count:          count,
+		MaskPath:       maskPath,
+		SafetySettings: safetySettings,
+	}
+
+	if dryRunSafety {
+		f.Progress("Classifying prompt safety with %s...", modelName)
+		ratings, err := client.ClassifySafety(ctx, prompt, config)
+		if err != nil {
+			if geminiErr, ok := err.(*gemini.GeminiError); ok {
+				f.Error("generate", geminiErr.Code, geminiErr.Message, "")
+			} else {
+				f.Error("generate", "CLASSIFY_FAILED", err.Error(), "")
+			}
+			return err
+		}
+		f.Success("generate", map[string]interface{}{
+			"prompt":         prompt,
+			"model":          modelName,
+			"safety_ratings": toOutputSafetyRatings(ratings),
+		}, &output.Timing{TotalMs: time.Since(startTime).Milliseconds()})
+		return nil
 	}
 
 	f.Progress("Generating image with %s...", modelName)
 
 	// Generate or edit
-	ctx := context.Background()
 	var images []*gemini.GeneratedImage
 
-	if inputPath != "" {
+	if len(inputPaths) > 0 {
 		// Edit mode
-		if _, err := os.Stat(inputPath); os.IsNotExist(err) {
-			f.Error("generate", "FILE_NOT_FOUND",
-				fmt.Sprintf("Input file not found: %s", inputPath), "")
-			return err
+		for _, p := range inputPaths {
+			if _, err := os.Stat(p); os.IsNotExist(err) {
+				f.Error("generate", "FILE_NOT_FOUND",
+					fmt.Sprintf("Input file not found: %s", p), "")
+				return err
+			}
 		}
-		f.Progress("Editing image: %s", inputPath)
-		images, err = client.EditImage(ctx, inputPath, prompt, config)
+		if maskPath != "" {
+			if _, err := os.Stat(maskPath); os.IsNotExist(err) {
+				f.Error("generate", "FILE_NOT_FOUND",
+					fmt.Sprintf("Mask file not found: %s", maskPath), "")
+				return err
+			}
+		}
+		f.Progress("Editing image(s): %s", strings.Join(inputPaths, ", "))
+		images, err = client.EditImage(ctx, inputPaths, prompt, config)
 	} else {
 		// Generate mode
 		images, err = client.GenerateImage(ctx, prompt, config)
@@ -197,25 +316,48 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 	for i, img := range images {
 		savePath := outputPath
 		if len(images) > 1 {
-			ext := filepath.Ext(outputPath)
-			base := strings.TrimSuffix(outputPath, ext)
-			savePath = fmt.Sprintf("%s_%d%s", base, i+1, ext)
+			savePath = suffixedPath(outputPath, i+1)
 		}
 
-		if err := client.SaveImage(img, savePath); err != nil {
+		originalData := img.Data
+
+		transformed, err := imaging.Apply(img.Data, img.MimeType, transformSpec)
+		if err != nil {
+			f.Error("generate", "TRANSFORM_FAILED", err.Error(), "")
+			return err
+		}
+		img.Data = transformed.Data
+		img.MimeType = transformed.MimeType
+
+		if err := client.SaveImage(ctx, img, savePath); err != nil {
 			f.Error("generate", "SAVE_FAILED", err.Error(), "")
 			return err
 		}
 
-		// Get dimensions (approximate based on mime type)
-		width, height := estimateDimensions(aspectRatio)
+		for size, thumbData := range transformed.Thumbnails {
+			thumbImg := &gemini.GeneratedImage{Data: thumbData, MimeType: transformed.MimeType}
+			if err := client.SaveImage(ctx, thumbImg, suffixedPath(savePath, size)); err != nil {
+				f.Error("generate", "SAVE_FAILED", err.Error(), "")
+				return err
+			}
+		}
+
+		meta, width, height, err := decodeImageMetadata(originalData, img, modelName, transformSpec.Resize)
+		if err != nil {
+			f.Error("generate", "METADATA_ERROR", err.Error(), "")
+			return err
+		}
+		meta.SafetyRatings = toOutputSafetyRatings(img.Safety)
 		f.ImageSaved(savePath, width, height)
 
 		results = append(results, output.ImageResult{
-			Path:   savePath,
-			Format: strings.TrimPrefix(img.MimeType, "image/"),
-			Size:   &output.ImageSize{Width: width, Height: height},
+			Path:     savePath,
+			Format:   strings.TrimPrefix(img.MimeType, "image/"),
+			Size:     &output.ImageSize{Width: width, Height: height},
+			Metadata: meta,
 		})
+
+		renderPreview(img.Data)
 	}
 
 	// Output success
@@ -283,27 +425,84 @@ func readStdin() (string, error) {
 	return strings.TrimSpace(builder.String()), nil
 }
 
-// estimateDimensions estimates image dimensions based on aspect ratio
-func estimateDimensions(ratio string) (int, int) {
-	// Default to 1024x1024 for 1:1
-	switch ratio {
-	case "1:1", "":
-		return 1024, 1024
-	case "16:9":
-		return 1024, 576
-	case "9:16":
-		return 576, 1024
-	case "4:3":
-		return 1024, 768
-	case "3:4":
-		return 768, 1024
-	case "3:2":
-		return 1024, 683
-	case "2:3":
-		return 683, 1024
-	case "21:9":
-		return 1024, 439
-	default:
-		return 1024, 1024
+// renderPreview renders data (the image just saved) inline in the terminal if
+// the user opted in via --preview or NANOBANANA_PREVIEW, the output isn't
+// JSON, and stdout is a TTY. It renders from the in-memory bytes rather than
+// re-reading the save path, since that path can be an "s3://"/"gs://" URI.
+func renderPreview(data []byte) {
+	requested := previewMode
+	if requested == "" {
+		requested = os.Getenv("NANOBANANA_PREVIEW")
+	}
+	if requested == "" {
+		return
+	}
+
+	protocol := preview.Resolve(requested)
+	if !preview.ShouldRender(protocol, formatStr == "json") {
+		return
+	}
+	if err := preview.Render(os.Stdout, data, protocol); err != nil {
+		fmt.Fprintf(os.Stderr, "preview: %s\n", err)
+	}
+}
+
+// toOutputSafetyRatings converts gemini's safety ratings into the decoupled
+// shape used by structured output.
+func toOutputSafetyRatings(ratings []gemini.SafetyRating) []output.SafetyRating {
+	if ratings == nil {
+		return nil
+	}
+	out := make([]output.SafetyRating, len(ratings))
+	for i, r := range ratings {
+		out[i] = output.SafetyRating{
+			Category:    string(r.Category),
+			Probability: r.Probability,
+			Blocked:     r.Blocked,
+		}
+	}
+	return out
+}
+
+// suffixedPath inserts "_N" before the extension of path, used when a
+// command produces more than one output image from a single base path.
+func suffixedPath(path string, n int) string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	return fmt.Sprintf("%s_%d%s", base, n, ext)
+}
+
+// decodeImageMetadata computes img's size and SHA-256 from the bytes actually
+// saved (post-transform), takes its MIME type from img.MimeType (sniffed from
+// those bytes only when that is empty), and its dimensions from resize (when
+// --transform resized it) or from originalData otherwise. Dimensions can't
+// always be read back from img.Data: --transform can hand it off to ffmpeg
+// for formats (AVIF, HEIC) the image package doesn't decode, but originalData
+// is always the API's own PNG/JPEG output and decodes natively.
+func decodeImageMetadata(originalData []byte, img *gemini.GeneratedImage, modelName string, resize *imaging.Resize) (*output.Metadata, int, int, error) {
+	var width, height int
+	if resize != nil {
+		width, height = resize.Width, resize.Height
+	} else {
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(originalData))
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to decode image metadata: %w", err)
+		}
+		width, height = cfg.Width, cfg.Height
+	}
+
+	sum := sha256.Sum256(img.Data)
+	mimeType := img.MimeType
+	if mimeType == "" {
+		mimeType = http.DetectContentType(img.Data)
+	}
+
+	meta := &output.Metadata{
+		Bytes:  int64(len(img.Data)),
+		SHA256: hex.EncodeToString(sum[:]),
+		Mime:   mimeType,
+		Model:  modelName,
+		Seed:   img.Seed,
 	}
+	return meta, width, height, nil
 }
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/lyalindotcom/nano-banana-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	apiKey    string
+	model     string
+	formatStr string
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "nanobanana",
+	Short: "Generate and edit images with Gemini's native image models",
+	// Every RunE already reports failures via the formatter (stderr text or
+	// a JSON envelope) before returning the error; let that be the only
+	// thing printed instead of Cobra's own "Error: ..." plus a full usage dump.
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&apiKey, "api-key", "", "Gemini API key (default: GEMINI_API_KEY env var)")
+	rootCmd.PersistentFlags().StringVarP(&model, "model", "m", "flash", "Model to use: flash, pro")
+	rootCmd.PersistentFlags().StringVar(&formatStr, "format", "text", "Output format: text, json")
+
+	rootCmd.AddCommand(generateCmd)
+}
+
+// Execute runs the root command.
+func Execute() error {
+	return rootCmd.Execute()
+}
+
+// GetFormatter returns the Formatter matching the --format flag.
+func GetFormatter() output.Formatter {
+	return output.New(output.Format(formatStr))
+}
+
+// GetAPIKey returns the API key from --api-key or the GEMINI_API_KEY env var.
+func GetAPIKey() string {
+	if apiKey != "" {
+		return apiKey
+	}
+	return os.Getenv("GEMINI_API_KEY")
+}
+
+// GetModel returns the model alias selected via --model.
+func GetModel() string {
+	return model
+}
@@ -0,0 +1,149 @@
+// Package gemini provides a thin client over the Gemini image generation API.
+package gemini
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lyalindotcom/nano-banana-cli/internal/storage"
+)
+
+// Error codes returned on GeminiError.Code.
+const (
+	ErrInvalidAPIKey  = "INVALID_API_KEY"
+	ErrQuotaExceeded  = "QUOTA_EXCEEDED"
+	ErrSafetyBlocked  = "SAFETY_BLOCKED"
+	ErrServerError    = "SERVER_ERROR"
+	ErrInvalidRequest = "INVALID_REQUEST"
+)
+
+// AspectRatios lists the aspect ratios accepted by the image generation API.
+var AspectRatios = []string{"1:1", "3:2", "2:3", "3:4", "4:3", "4:5", "5:4", "9:16", "16:9", "21:9"}
+
+// GeminiError wraps an error returned by the Gemini API with a stable code
+// so callers can branch on failure type without parsing message text.
+type GeminiError struct {
+	Code    string
+	Message string
+}
+
+func (e *GeminiError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// ImageConfig holds the generation parameters shared by text-to-image and
+// image-editing requests.
+type ImageConfig struct {
+	AspectRatio    string
+	Resolution     string
+	Count          int
+	Model          string
+	Seed           *int64
+	NegativePrompt string
+	// MaskPath, if set, is an alpha-mask image included alongside the edit
+	// inputs and called out in the prompt preamble for inpainting.
+	MaskPath string
+	// SafetySettings overrides the default blocking threshold per category.
+	SafetySettings []SafetySetting
+}
+
+// GeneratedImage is a single image returned by the API, still in memory.
+type GeneratedImage struct {
+	Data     []byte
+	MimeType string
+	// Seed is the seed the API actually used, when it echoes one back.
+	Seed *int64
+	// Safety holds the per-category ratings the API returned for this image.
+	Safety []SafetyRating
+}
+
+// Client talks to the Gemini image generation API.
+type Client struct {
+	apiKey    string
+	modelName string
+	timeout   time.Duration
+}
+
+// NewClient validates apiKey and modelName and returns a ready-to-use Client.
+func NewClient(apiKey, modelName string, timeout time.Duration) (*Client, error) {
+	if apiKey == "" {
+		return nil, &GeminiError{Code: ErrInvalidAPIKey, Message: "API key is required"}
+	}
+	return &Client{apiKey: apiKey, modelName: modelName, timeout: timeout}, nil
+}
+
+// GenerateImage produces config.Count images from a text prompt.
+func (c *Client) GenerateImage(ctx context.Context, prompt string, config *ImageConfig) ([]*GeneratedImage, error) {
+	return c.request(ctx, &Content{Prompt: prompt}, config)
+}
+
+// EditImage edits or composes inputPaths (in order) according to prompt and
+// returns the resulting images. A single path behaves as a simple edit;
+// multiple paths let the prompt reference each input positionally, e.g.
+// "place the subject from image 1 into the scene from image 2".
+func (c *Client) EditImage(ctx context.Context, inputPaths []string, prompt string, config *ImageConfig) ([]*GeneratedImage, error) {
+	if len(inputPaths) == 0 {
+		return nil, &GeminiError{Code: ErrInvalidRequest, Message: "EditImage requires at least one input image"}
+	}
+	for _, p := range inputPaths {
+		if _, err := os.Stat(p); err != nil {
+			return nil, &GeminiError{Code: ErrInvalidRequest, Message: fmt.Sprintf("input image not found: %s", p)}
+		}
+	}
+	if config != nil && config.MaskPath != "" {
+		if _, err := os.Stat(config.MaskPath); err != nil {
+			return nil, &GeminiError{Code: ErrInvalidRequest, Message: fmt.Sprintf("mask image not found: %s", config.MaskPath)}
+		}
+	}
+
+	content, err := buildEditContent(inputPaths, prompt, config)
+	if err != nil {
+		return nil, &GeminiError{Code: ErrInvalidRequest, Message: err.Error()}
+	}
+
+	return c.request(ctx, content, config)
+}
+
+// request is the shared path for text-to-image and image-editing calls.
+func (c *Client) request(ctx context.Context, content *Content, config *ImageConfig) ([]*GeneratedImage, error) {
+	// NOTE: actual HTTP/SDK call to the Gemini API lives here in the full
+	// implementation; omitted in this snapshot.
+	return nil, &GeminiError{Code: ErrServerError, Message: "not implemented in this snapshot"}
+}
+
+// SaveImage writes img to uri via the storage backend matching its scheme
+// (a local path, or an "s3://"/"gs://" URI).
+func (c *Client) SaveImage(ctx context.Context, img *GeneratedImage, uri string) error {
+	w, err := storage.Create(ctx, uri)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(img.Data); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// IsValidAspectRatio reports whether ratio is one of AspectRatios.
+func IsValidAspectRatio(ratio string) bool {
+	for _, r := range AspectRatios {
+		if r == ratio {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveModelName maps a user-facing model alias (flash, pro) to the
+// concrete Gemini model identifier.
+func ResolveModelName(model string) string {
+	switch model {
+	case "pro":
+		return "gemini-3-pro-image"
+	default:
+		return "gemini-2.5-flash-image"
+	}
+}
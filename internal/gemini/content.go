@@ -0,0 +1,66 @@
+package gemini
+
+import (
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+)
+
+// ImagePart is one inline image blob sent alongside a prompt.
+type ImagePart struct {
+	Data     []byte
+	MimeType string
+}
+
+// Content is a multi-part request body: an ordered list of image inputs plus
+// a single text prompt, mirroring the Gemini multimodal request shape.
+type Content struct {
+	Prompt string
+	Images []ImagePart
+	Mask   *ImagePart
+}
+
+// buildEditContent reads inputPaths (and the optional mask from config) in
+// order, detects each one's MIME type, and assembles the Content sent to
+// EditImage. The prompt is prefixed with a short preamble describing each
+// image's position and, when present, the mask's role.
+func buildEditContent(inputPaths []string, prompt string, config *ImageConfig) (*Content, error) {
+	content := &Content{}
+
+	var preamble string
+	for i, p := range inputPaths {
+		data, mimeType, err := readImageFile(p)
+		if err != nil {
+			return nil, err
+		}
+		content.Images = append(content.Images, ImagePart{Data: data, MimeType: mimeType})
+		preamble += fmt.Sprintf("Image %d: %s\n", i+1, filepath.Base(p))
+	}
+
+	if config != nil && config.MaskPath != "" {
+		data, mimeType, err := readImageFile(config.MaskPath)
+		if err != nil {
+			return nil, err
+		}
+		content.Mask = &ImagePart{Data: data, MimeType: mimeType}
+		preamble += "Mask: an alpha mask marking the region of image 1 to edit; transparent areas are preserved.\n"
+	}
+
+	content.Prompt = preamble + prompt
+	return content, nil
+}
+
+// readImageFile loads path and detects its MIME type from the file extension.
+func readImageFile(path string) ([]byte, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	mimeType := mime.TypeByExtension(filepath.Ext(path))
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	return data, mimeType, nil
+}
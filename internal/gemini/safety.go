@@ -0,0 +1,85 @@
+package gemini
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SafetyCategory is one of the harm categories the Gemini API classifies.
+type SafetyCategory string
+
+const (
+	SafetyHarassment       SafetyCategory = "harassment"
+	SafetyHate             SafetyCategory = "hate"
+	SafetySexuallyExplicit SafetyCategory = "sexually_explicit"
+	SafetyDangerousContent SafetyCategory = "dangerous_content"
+)
+
+// SafetyThreshold is the blocking sensitivity for a category.
+type SafetyThreshold string
+
+const (
+	BlockNone           SafetyThreshold = "block_none"
+	BlockOnlyHigh       SafetyThreshold = "block_only_high"
+	BlockLowAndAbove    SafetyThreshold = "block_low_and_above"
+	BlockMediumAndAbove SafetyThreshold = "block_medium_and_above"
+)
+
+// SafetySetting tunes how aggressively one category is blocked, translated
+// onto the request as a SafetySetting entry.
+type SafetySetting struct {
+	Category  SafetyCategory
+	Threshold SafetyThreshold
+}
+
+// SafetyRating is the classifier's verdict for one category on a response.
+type SafetyRating struct {
+	Category    SafetyCategory `json:"category"`
+	Probability string         `json:"probability"`
+	Blocked     bool           `json:"blocked"`
+}
+
+var validCategories = map[SafetyCategory]bool{
+	SafetyHarassment: true, SafetyHate: true, SafetySexuallyExplicit: true, SafetyDangerousContent: true,
+}
+
+var validThresholds = map[SafetyThreshold]bool{
+	BlockNone: true, BlockOnlyHigh: true, BlockLowAndAbove: true, BlockMediumAndAbove: true,
+}
+
+// ParseSafetySettings parses a "--safety" flag value such as
+// "harassment=block_none,hate=block_low_and_above" into SafetySetting entries.
+func ParseSafetySettings(spec string) ([]SafetySetting, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	var settings []SafetySetting
+	for _, part := range strings.Split(spec, ",") {
+		category, threshold, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid safety setting %q: expected category=threshold", part)
+		}
+		if !validCategories[SafetyCategory(category)] {
+			return nil, fmt.Errorf("invalid safety category %q", category)
+		}
+		if !validThresholds[SafetyThreshold(threshold)] {
+			return nil, fmt.Errorf("invalid safety threshold %q for category %q", threshold, category)
+		}
+		settings = append(settings, SafetySetting{
+			Category:  SafetyCategory(category),
+			Threshold: SafetyThreshold(threshold),
+		})
+	}
+	return settings, nil
+}
+
+// ClassifySafety runs only the safety classifier for prompt, without
+// generating or saving image bytes, so callers can pre-screen prompts in
+// bulk via --dry-run-safety.
+func (c *Client) ClassifySafety(ctx context.Context, prompt string, config *ImageConfig) ([]SafetyRating, error) {
+	// NOTE: the full implementation calls the classify-only endpoint; omitted
+	// in this snapshot, matching the rest of Client.request.
+	return nil, &GeminiError{Code: ErrServerError, Message: "not implemented in this snapshot"}
+}
@@ -0,0 +1,65 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// encodeViaFFmpeg handles formats the standard library can't encode (WebP,
+// AVIF, HEIC) by round-tripping through a temporary PNG and shelling out to
+// the system ffmpeg binary.
+func encodeViaFFmpeg(img image.Image, format string, quality int) ([]byte, error) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return nil, fmt.Errorf("encoding to %s requires ffmpeg, which was not found on PATH", format)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "nanobanana-transform-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	inPath := filepath.Join(tmpDir, "in.png")
+	outPath := filepath.Join(tmpDir, "out."+format)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(inPath, buf.Bytes(), 0o644); err != nil {
+		return nil, err
+	}
+
+	args := []string{"-y", "-i", inPath}
+	if quality > 0 {
+		args = append(args, "-q:v", fmt.Sprintf("%d", ffmpegQuality(quality)))
+	}
+	args = append(args, outPath)
+
+	cmd := exec.Command("ffmpeg", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg failed converting to %s: %w: %s", format, err, stderr.String())
+	}
+
+	return os.ReadFile(outPath)
+}
+
+// ffmpegQuality maps our 0-100 quality scale (higher is better, matching
+// jpeg.Options) onto ffmpeg's -q:v scale (lower is better, roughly 1-31).
+func ffmpegQuality(quality int) int {
+	q := 31 - (quality*30)/100
+	if q < 1 {
+		q = 1
+	}
+	if q > 31 {
+		q = 31
+	}
+	return q
+}
@@ -0,0 +1,163 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+// goFormats are the formats Go's standard library can encode directly.
+// golang.org/x/image/webp only decodes WebP, so it (like AVIF and HEIC) is
+// handed to ffmpeg instead.
+var goFormats = map[string]bool{"png": true, "jpeg": true, "jpg": true}
+
+// Result is the output of applying a Spec to one generated image.
+type Result struct {
+	Data       []byte
+	MimeType   string
+	Thumbnails map[int][]byte // keyed by size, same format as Data
+}
+
+// Apply runs spec against the image in data (detected by mimeType) and
+// returns the transformed image plus any requested thumbnails.
+func Apply(data []byte, mimeType string, spec *Spec) (*Result, error) {
+	if spec == nil || (spec.Resize == nil && spec.Format == "" && len(spec.ThumbnailSizes) == 0) {
+		return &Result{Data: data, MimeType: mimeType}, nil
+	}
+
+	img, err := decode(data, mimeType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image for transform: %w", err)
+	}
+
+	outFormat := spec.Format
+	if outFormat == "" {
+		outFormat = formatFromMime(mimeType)
+	}
+
+	result := &Result{MimeType: "image/" + outFormat}
+
+	main := img
+	if spec.Resize != nil {
+		main = resize(img, spec.Resize.Width, spec.Resize.Height, spec.Resize.Mode)
+	}
+	result.Data, err = encode(main, outFormat, spec.Quality)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(spec.ThumbnailSizes) > 0 {
+		result.Thumbnails = map[int][]byte{}
+		for _, size := range spec.ThumbnailSizes {
+			thumb := resize(img, size, size, FitContain)
+			encoded, err := encode(thumb, outFormat, spec.Quality)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode %dpx thumbnail: %w", size, err)
+			}
+			result.Thumbnails[size] = encoded
+		}
+	}
+
+	return result, nil
+}
+
+func decode(data []byte, mimeType string) (image.Image, error) {
+	switch formatFromMime(mimeType) {
+	case "png":
+		return png.Decode(bytes.NewReader(data))
+	case "jpeg", "jpg":
+		return jpeg.Decode(bytes.NewReader(data))
+	case "webp":
+		return webp.Decode(bytes.NewReader(data))
+	default:
+		img, _, err := image.Decode(bytes.NewReader(data))
+		return img, err
+	}
+}
+
+func encode(img image.Image, format string, quality int) ([]byte, error) {
+	if !goFormats[format] {
+		return encodeViaFFmpeg(img, format, quality)
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "jpeg", "jpg":
+		q := quality
+		if q == 0 {
+			q = jpeg.DefaultQuality
+		}
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: q}); err != nil {
+			return nil, err
+		}
+	default:
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// resize scales src to (width, height) using mode:
+//   - FitContain preserves aspect ratio and letterboxes with transparent padding
+//   - FitCrop fills the target and trims the overflow from the center
+func resize(src image.Image, width, height int, mode FitMode) image.Image {
+	srcBounds := src.Bounds()
+	sw, sh := srcBounds.Dx(), srcBounds.Dy()
+
+	hScale := float64(width) / float64(sw)
+	vScale := float64(height) / float64(sh)
+	scale := hScale
+	switch mode {
+	case FitContain:
+		if vScale < scale {
+			scale = vScale
+		}
+	case FitCrop:
+		if vScale > scale {
+			scale = vScale
+		}
+	}
+
+	scaledW := int(float64(sw) * scale)
+	scaledH := int(float64(sh) * scale)
+	scaled := image.NewRGBA(image.Rect(0, 0, scaledW, scaledH))
+	draw.CatmullRom.Scale(scaled, scaled.Bounds(), src, srcBounds, draw.Over, nil)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	if mode == FitCrop {
+		// scaled covers the whole target box; trim the centered overflow.
+		offsetX := (scaledW - width) / 2
+		offsetY := (scaledH - height) / 2
+		draw.Draw(dst, dst.Bounds(), scaled, image.Pt(offsetX, offsetY), draw.Src)
+		return dst
+	}
+
+	// scaled fits entirely within the target box; letterbox the remainder
+	// with transparent padding.
+	offsetX := (width - scaledW) / 2
+	offsetY := (height - scaledH) / 2
+	destRect := image.Rect(offsetX, offsetY, offsetX+scaledW, offsetY+scaledH)
+	draw.Draw(dst, destRect, scaled, image.Point{}, draw.Src)
+	return dst
+}
+
+func formatFromMime(mimeType string) string {
+	switch mimeType {
+	case "image/jpeg", "jpeg", "jpg":
+		return "jpeg"
+	case "image/webp", "webp":
+		return "webp"
+	case "image/avif", "avif":
+		return "avif"
+	case "image/heic", "heic":
+		return "heic"
+	default:
+		return "png"
+	}
+}
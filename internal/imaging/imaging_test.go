@@ -0,0 +1,67 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func solidImage(w, h int, c color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestResizeFitContainLetterboxesAndPreservesAspect(t *testing.T) {
+	src := solidImage(400, 200, color.White) // 2:1 landscape
+	out := resize(src, 100, 100, FitContain)
+
+	bounds := out.Bounds()
+	if bounds.Dx() != 100 || bounds.Dy() != 100 {
+		t.Fatalf("output bounds = %v, want 100x100", bounds)
+	}
+
+	// The scaled image (100x50) should be letterboxed around the vertical
+	// center, so the top row stays transparent padding.
+	_, _, _, a := out.At(50, 0).RGBA()
+	if a != 0 {
+		t.Errorf("top row alpha = %d, want 0 (letterboxed)", a)
+	}
+	_, _, _, a = out.At(50, 50).RGBA()
+	if a == 0 {
+		t.Errorf("center alpha = 0, want opaque source content")
+	}
+}
+
+func TestResizeFitCropFillsTargetAndTrimsOverflow(t *testing.T) {
+	src := solidImage(400, 200, color.White) // 2:1 landscape
+	out := resize(src, 100, 100, FitCrop)
+
+	bounds := out.Bounds()
+	if bounds.Dx() != 100 || bounds.Dy() != 100 {
+		t.Fatalf("output bounds = %v, want 100x100", bounds)
+	}
+
+	// FitCrop fills the whole target box, so every pixel is opaque source
+	// content - no letterboxing.
+	for _, pt := range []image.Point{{0, 0}, {99, 0}, {50, 50}, {0, 99}, {99, 99}} {
+		_, _, _, a := out.At(pt.X, pt.Y).RGBA()
+		if a == 0 {
+			t.Errorf("At(%v) alpha = 0, want opaque (no letterboxing under crop)", pt)
+		}
+	}
+}
+
+func TestResizeSquareToSquareIsExact(t *testing.T) {
+	src := solidImage(50, 50, color.White)
+	for _, mode := range []FitMode{FitContain, FitCrop} {
+		out := resize(src, 200, 200, mode)
+		if out.Bounds().Dx() != 200 || out.Bounds().Dy() != 200 {
+			t.Errorf("mode %q: bounds = %v, want 200x200", mode, out.Bounds())
+		}
+	}
+}
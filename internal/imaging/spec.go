@@ -0,0 +1,119 @@
+// Package imaging applies a post-generation transform pipeline (resize,
+// format conversion, thumbnails) to images before they're saved.
+package imaging
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FitMode selects how Resize reconciles the source aspect ratio with the
+// requested target size.
+type FitMode string
+
+const (
+	// FitContain preserves aspect ratio and letterboxes to the target size.
+	FitContain FitMode = "fit"
+	// FitCrop fills the target size and trims the overflow from the center.
+	FitCrop FitMode = "crop"
+)
+
+// Resize describes a single resize step.
+type Resize struct {
+	Width  int
+	Height int
+	Mode   FitMode
+}
+
+// Spec is a parsed --transform pipeline.
+type Spec struct {
+	Resize         *Resize
+	Format         string // target encode format, e.g. "webp"; "" keeps the original
+	Quality        int    // 0 means use the format's default
+	ThumbnailSizes []int  // square thumbnail sizes to write as siblings
+}
+
+// Parse parses a --transform spec such as
+// "resize=1024x1024:fit,format=webp,quality=85,thumbnail=256".
+func Parse(spec string) (*Spec, error) {
+	s := &Spec{}
+	if strings.TrimSpace(spec) == "" {
+		return s, nil
+	}
+
+	for _, part := range strings.Split(spec, ",") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid transform step %q: expected key=value", part)
+		}
+		switch key {
+		case "resize":
+			resize, err := parseResize(value)
+			if err != nil {
+				return nil, err
+			}
+			s.Resize = resize
+		case "format":
+			s.Format = value
+		case "quality":
+			q, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid quality %q: %w", value, err)
+			}
+			s.Quality = q
+		case "thumbnail":
+			size, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid thumbnail size %q: %w", value, err)
+			}
+			s.ThumbnailSizes = append(s.ThumbnailSizes, size)
+		default:
+			return nil, fmt.Errorf("unknown transform step %q", key)
+		}
+	}
+	return s, nil
+}
+
+// ParseThumbnailSizes parses the comma-separated value of --thumbnail-sizes,
+// e.g. "96,256,512".
+func ParseThumbnailSizes(csv string) ([]int, error) {
+	if strings.TrimSpace(csv) == "" {
+		return nil, nil
+	}
+	var sizes []int
+	for _, part := range strings.Split(csv, ",") {
+		size, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("invalid thumbnail size %q: %w", part, err)
+		}
+		sizes = append(sizes, size)
+	}
+	return sizes, nil
+}
+
+// parseResize parses "WxH" or "WxH:mode" into a Resize.
+func parseResize(value string) (*Resize, error) {
+	dims, modeStr, _ := strings.Cut(value, ":")
+	w, h, ok := strings.Cut(dims, "x")
+	if !ok {
+		return nil, fmt.Errorf("invalid resize dimensions %q: expected WxH", dims)
+	}
+	width, err := strconv.Atoi(w)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resize width %q: %w", w, err)
+	}
+	height, err := strconv.Atoi(h)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resize height %q: %w", h, err)
+	}
+
+	mode := FitContain
+	if modeStr != "" {
+		mode = FitMode(modeStr)
+		if mode != FitContain && mode != FitCrop {
+			return nil, fmt.Errorf("invalid resize mode %q: expected fit or crop", modeStr)
+		}
+	}
+	return &Resize{Width: width, Height: height, Mode: mode}, nil
+}
@@ -0,0 +1,101 @@
+package imaging
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	spec, err := Parse("resize=1024x768:fit,format=webp,quality=85,thumbnail=256,thumbnail=64")
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if spec.Resize == nil || spec.Resize.Width != 1024 || spec.Resize.Height != 768 || spec.Resize.Mode != FitContain {
+		t.Errorf("Resize = %+v, want {1024 768 fit}", spec.Resize)
+	}
+	if spec.Format != "webp" {
+		t.Errorf("Format = %q, want %q", spec.Format, "webp")
+	}
+	if spec.Quality != 85 {
+		t.Errorf("Quality = %d, want 85", spec.Quality)
+	}
+	if want := []int{256, 64}; len(spec.ThumbnailSizes) != len(want) || spec.ThumbnailSizes[0] != want[0] || spec.ThumbnailSizes[1] != want[1] {
+		t.Errorf("ThumbnailSizes = %v, want %v", spec.ThumbnailSizes, want)
+	}
+}
+
+func TestParseEmpty(t *testing.T) {
+	spec, err := Parse("")
+	if err != nil {
+		t.Fatalf("Parse(\"\") returned error: %v", err)
+	}
+	if spec.Resize != nil || spec.Format != "" || spec.Quality != 0 || spec.ThumbnailSizes != nil {
+		t.Errorf("Parse(\"\") = %+v, want zero value", spec)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []string{
+		"resize",
+		"resize=1024",
+		"resize=abcxdef",
+		"resize=100x100:diagonal",
+		"quality=high",
+		"thumbnail=big",
+		"unknown=1",
+	}
+	for _, spec := range cases {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) = nil error, want error", spec)
+		}
+	}
+}
+
+func TestParseResizeDefaultsToFit(t *testing.T) {
+	resize, err := parseResize("512x256")
+	if err != nil {
+		t.Fatalf("parseResize returned error: %v", err)
+	}
+	if resize.Width != 512 || resize.Height != 256 || resize.Mode != FitContain {
+		t.Errorf("parseResize(%q) = %+v, want {512 256 fit}", "512x256", resize)
+	}
+}
+
+func TestParseResizeCrop(t *testing.T) {
+	resize, err := parseResize("200x200:crop")
+	if err != nil {
+		t.Fatalf("parseResize returned error: %v", err)
+	}
+	if resize.Mode != FitCrop {
+		t.Errorf("Mode = %q, want %q", resize.Mode, FitCrop)
+	}
+}
+
+func TestParseThumbnailSizes(t *testing.T) {
+	sizes, err := ParseThumbnailSizes("96, 256,512")
+	if err != nil {
+		t.Fatalf("ParseThumbnailSizes returned error: %v", err)
+	}
+	want := []int{96, 256, 512}
+	if len(sizes) != len(want) {
+		t.Fatalf("len(sizes) = %d, want %d", len(sizes), len(want))
+	}
+	for i := range want {
+		if sizes[i] != want[i] {
+			t.Errorf("sizes[%d] = %d, want %d", i, sizes[i], want[i])
+		}
+	}
+}
+
+func TestParseThumbnailSizesEmpty(t *testing.T) {
+	sizes, err := ParseThumbnailSizes("")
+	if err != nil {
+		t.Fatalf("ParseThumbnailSizes(\"\") returned error: %v", err)
+	}
+	if sizes != nil {
+		t.Errorf("ParseThumbnailSizes(\"\") = %v, want nil", sizes)
+	}
+}
+
+func TestParseThumbnailSizesInvalid(t *testing.T) {
+	if _, err := ParseThumbnailSizes("96,abc"); err == nil {
+		t.Error("ParseThumbnailSizes(\"96,abc\") = nil error, want error")
+	}
+}
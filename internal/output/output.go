@@ -0,0 +1,137 @@
+// Package output renders command results as either human-readable text or
+// machine-readable JSON, so the CLI composes cleanly with scripts and agents.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// ImageSize is the pixel dimensions of a saved image.
+type ImageSize struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// ImageResult describes one saved image in a command's structured output.
+type ImageResult struct {
+	Path     string     `json:"path"`
+	Format   string     `json:"format"`
+	Size     *ImageSize `json:"size,omitempty"`
+	Metadata *Metadata  `json:"metadata,omitempty"`
+}
+
+// Metadata carries the ground-truth facts about a saved image, decoded from
+// the bytes actually written rather than inferred from request parameters.
+type Metadata struct {
+	Bytes         int64          `json:"bytes"`
+	SHA256        string         `json:"sha256"`
+	Mime          string         `json:"mime"`
+	Model         string         `json:"model"`
+	Seed          *int64         `json:"seed,omitempty"`
+	SafetyRatings []SafetyRating `json:"safety_ratings,omitempty"`
+}
+
+// SafetyRating is one category's classifier verdict on a generated image.
+type SafetyRating struct {
+	Category    string `json:"category"`
+	Probability string `json:"probability"`
+	Blocked     bool   `json:"blocked"`
+}
+
+// Timing reports how long a command took to run.
+type Timing struct {
+	TotalMs int64 `json:"total_ms"`
+}
+
+// Formatter renders progress, errors, and success results for a command.
+type Formatter interface {
+	Progress(format string, args ...interface{})
+	Error(command, code, message, hint string)
+	Success(command string, data interface{}, timing *Timing)
+	ImageSaved(path string, width, height int)
+	// Record streams a single JSONL record to stdout, independent of the
+	// command's final Success/Error envelope. Used by commands (e.g. batch)
+	// that emit one result per unit of work as they complete.
+	Record(data interface{})
+}
+
+// Format selects which Formatter implementation to use.
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+)
+
+// New returns the Formatter for the given format, defaulting to text.
+func New(format Format) Formatter {
+	if format == FormatJSON {
+		return &jsonFormatter{}
+	}
+	return &textFormatter{}
+}
+
+type textFormatter struct{}
+
+func (f *textFormatter) Progress(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "%s\n", fmt.Sprintf(format, args...))
+}
+
+func (f *textFormatter) Error(command, code, message, hint string) {
+	fmt.Fprintf(os.Stderr, "error: %s\n", message)
+	if hint != "" {
+		fmt.Fprintf(os.Stderr, "hint: %s\n", hint)
+	}
+}
+
+func (f *textFormatter) Success(command string, data interface{}, timing *Timing) {
+	fmt.Printf("%s: done\n", command)
+}
+
+func (f *textFormatter) ImageSaved(path string, width, height int) {
+	fmt.Printf("saved %s (%dx%d)\n", path, width, height)
+}
+
+func (f *textFormatter) Record(data interface{}) {
+	json.NewEncoder(os.Stdout).Encode(data)
+}
+
+type jsonFormatter struct{}
+
+type jsonEnvelope struct {
+	Command string      `json:"command"`
+	Status  string      `json:"status"`
+	Data    interface{} `json:"data,omitempty"`
+	Error   *jsonError  `json:"error,omitempty"`
+	Timing  *Timing     `json:"timing,omitempty"`
+}
+
+type jsonError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+func (f *jsonFormatter) Progress(format string, args ...interface{}) {
+	// JSON output stays clean; progress is not emitted on stdout.
+}
+
+func (f *jsonFormatter) Error(command, code, message, hint string) {
+	env := jsonEnvelope{Command: command, Status: "error", Error: &jsonError{Code: code, Message: message, Hint: hint}}
+	json.NewEncoder(os.Stdout).Encode(env)
+}
+
+func (f *jsonFormatter) Success(command string, data interface{}, timing *Timing) {
+	env := jsonEnvelope{Command: command, Status: "success", Data: data, Timing: timing}
+	json.NewEncoder(os.Stdout).Encode(env)
+}
+
+func (f *jsonFormatter) ImageSaved(path string, width, height int) {
+	// Represented in the Success payload's image list instead of emitted here.
+}
+
+func (f *jsonFormatter) Record(data interface{}) {
+	json.NewEncoder(os.Stdout).Encode(data)
+}
@@ -0,0 +1,133 @@
+// Package preview renders a saved image inline in terminals that support a
+// graphics protocol (Kitty, iTerm2, or sixel), so users can see generations
+// without leaving the terminal.
+package preview
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"os"
+)
+
+// Protocol identifies which terminal graphics protocol to use.
+type Protocol string
+
+const (
+	ProtocolAuto  Protocol = "auto"
+	ProtocolKitty Protocol = "kitty"
+	ProtocolITerm Protocol = "iterm"
+	ProtocolSixel Protocol = "sixel"
+	ProtocolNone  Protocol = "none"
+)
+
+const kittyChunkSize = 4096
+
+// Detect picks a protocol from the environment the way terminals advertise
+// themselves: Kitty sets $KITTY_WINDOW_ID, iTerm2 and WezTerm set
+// $TERM_PROGRAM. $TERM is checked last: "xterm-kitty" selects Kitty, and the
+// sixel terminals "mlterm" and "yaft-256color" select the sixel encoder.
+func Detect() Protocol {
+	if os.Getenv("KITTY_WINDOW_ID") != "" {
+		return ProtocolKitty
+	}
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm":
+		return ProtocolITerm
+	}
+	if term := os.Getenv("TERM"); term != "" {
+		switch term {
+		case "xterm-kitty":
+			return ProtocolKitty
+		case "mlterm", "yaft-256color":
+			return ProtocolSixel
+		}
+	}
+	return ProtocolNone
+}
+
+// Resolve turns the --preview flag value / NANOBANANA_PREVIEW env var into a
+// concrete Protocol, running detection for "auto".
+func Resolve(requested string) Protocol {
+	switch Protocol(requested) {
+	case ProtocolKitty, ProtocolITerm, ProtocolSixel, ProtocolNone:
+		return Protocol(requested)
+	default:
+		return Detect()
+	}
+}
+
+// ShouldRender reports whether a preview should be attempted: stdout must be
+// a TTY, the caller must not be emitting machine-readable JSON, and a
+// protocol must have been resolved.
+func ShouldRender(protocol Protocol, jsonOutput bool) bool {
+	if protocol == ProtocolNone || jsonOutput {
+		return false
+	}
+	stat, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return (stat.Mode() & os.ModeCharDevice) != 0
+}
+
+// Render writes an inline preview of the PNG/JPEG image in data to out using
+// protocol. data is the in-memory image the caller just saved, not re-read
+// from its destination: the save path can be an "s3://"/"gs://" URI that
+// os.ReadFile can't reach.
+func Render(out io.Writer, data []byte, protocol Protocol) error {
+	switch protocol {
+	case ProtocolKitty:
+		return renderKitty(out, data)
+	case ProtocolITerm:
+		return renderITerm(out, data)
+	case ProtocolSixel:
+		return renderSixel(out, data)
+	default:
+		return nil
+	}
+}
+
+// renderKitty emits the Kitty terminal graphics protocol escape sequence,
+// splitting the base64 payload into <=4096-byte chunks as the protocol
+// requires, with m=1 marking all but the final chunk.
+func renderKitty(out io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	for len(encoded) > 0 {
+		chunkLen := kittyChunkSize
+		if chunkLen > len(encoded) {
+			chunkLen = len(encoded)
+		}
+		chunk := encoded[:chunkLen]
+		encoded = encoded[chunkLen:]
+
+		more := 0
+		if len(encoded) > 0 {
+			more = 1
+		}
+
+		if _, err := fmt.Fprintf(out, "\x1b_Ga=T,f=100,m=%d;%s\x1b\\", more, chunk); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintln(out)
+	return nil
+}
+
+// renderITerm emits the iTerm2 inline image escape sequence.
+func renderITerm(out io.Writer, data []byte) error {
+	width, height := 0, 0
+	if cfg, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		width, height = cfg.Width, cfg.Height
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(data)
+	_, err := fmt.Fprintf(out, "\x1b]1337;File=inline=1;size=%d;width=%dpx;height=%dpx:%s\a\n",
+		len(data), width, height, encoded)
+	return err
+}
@@ -0,0 +1,81 @@
+package preview
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+)
+
+// renderSixel converts the decoded image to the DEC sixel format and writes
+// the escape sequence to out. It quantizes to a 16-color palette, which is
+// enough for a quick preview without pulling in a full image-processing
+// dependency.
+func renderSixel(out io.Writer, data []byte) error {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to decode image for sixel preview: %w", err)
+	}
+
+	palette := sixelPalette()
+	bounds := img.Bounds()
+
+	var buf bytes.Buffer
+	buf.WriteString("\x1bPq\n")
+	for i, c := range palette {
+		r, g, b, _ := c.RGBA()
+		fmt.Fprintf(&buf, "#%d;2;%d;%d;%d", i, r*100/0xffff, g*100/0xffff, b*100/0xffff)
+	}
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += 6 {
+		for ci := range palette {
+			buf.WriteString(fmt.Sprintf("#%d", ci))
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				sixelByte := byte(0)
+				for row := 0; row < 6 && y+row < bounds.Max.Y; row++ {
+					if closestIndex(img.At(x, y+row), palette) == ci {
+						sixelByte |= 1 << uint(row)
+					}
+				}
+				buf.WriteByte('?' + sixelByte)
+			}
+			buf.WriteString("$\n")
+		}
+		buf.WriteString("-\n")
+	}
+	buf.WriteString("\x1b\\")
+
+	_, err = out.Write(buf.Bytes())
+	return err
+}
+
+// sixelPalette returns a small fixed palette used to quantize preview images.
+func sixelPalette() []color.Color {
+	levels := []uint8{0, 85, 170, 255}
+	var palette []color.Color
+	for _, r := range levels {
+		for _, g := range levels {
+			palette = append(palette, color.RGBA{R: r, G: g, B: 255 - r, A: 255})
+		}
+	}
+	return palette
+}
+
+func closestIndex(c color.Color, palette []color.Color) int {
+	cr, cg, cb, _ := c.RGBA()
+	best, bestDist := 0, uint32(1<<32-1)
+	for i, p := range palette {
+		pr, pg, pb, _ := p.RGBA()
+		dist := sqDiff(cr, pr) + sqDiff(cg, pg) + sqDiff(cb, pb)
+		if dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+	return best
+}
+
+func sqDiff(a, b uint32) uint32 {
+	d := int64(a) - int64(b)
+	return uint32((d * d) >> 16)
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fileWriter writes to the local filesystem. It is the default backend for
+// any URI without a scheme, and also handles explicit "file://" URIs.
+type fileWriter struct{}
+
+func localPath(uri string) string {
+	return strings.TrimPrefix(uri, "file://")
+}
+
+func (f *fileWriter) Create(ctx context.Context, uri string) (io.WriteCloser, error) {
+	path := localPath(uri)
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	return os.Create(path)
+}
+
+func (f *fileWriter) Exists(ctx context.Context, uri string) (bool, error) {
+	_, err := os.Stat(localPath(uri))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (f *fileWriter) Stat(ctx context.Context, uri string) (Info, error) {
+	info, err := os.Stat(localPath(uri))
+	if err != nil {
+		return Info{}, err
+	}
+	return Info{Size: info.Size()}, nil
+}
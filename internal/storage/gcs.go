@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	gcs "cloud.google.com/go/storage"
+)
+
+// gcsWriter writes to Google Cloud Storage. Credentials come from
+// GOOGLE_APPLICATION_CREDENTIALS / the ambient metadata server, picked up by
+// gcs.NewClient's default credential chain.
+type gcsWriter struct{}
+
+func init() {
+	Register("gs", &gcsWriter{})
+}
+
+func (w *gcsWriter) Create(ctx context.Context, uri string) (io.WriteCloser, error) {
+	bucket, object, err := splitBucketObject(uri)
+	if err != nil {
+		return nil, err
+	}
+	client, err := gcs.NewClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.Bucket(bucket).Object(object).NewWriter(ctx), nil
+}
+
+func (w *gcsWriter) Exists(ctx context.Context, uri string) (bool, error) {
+	_, err := w.Stat(ctx, uri)
+	if err != nil {
+		if errors.Is(err, gcs.ErrObjectNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (w *gcsWriter) Stat(ctx context.Context, uri string) (Info, error) {
+	bucket, object, err := splitBucketObject(uri)
+	if err != nil {
+		return Info{}, err
+	}
+	client, err := gcs.NewClient(ctx)
+	if err != nil {
+		return Info{}, err
+	}
+	attrs, err := client.Bucket(bucket).Object(object).Attrs(ctx)
+	if err != nil {
+		return Info{}, err
+	}
+	return Info{Size: attrs.Size}, nil
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// s3Writer writes to Amazon S3 (or any S3-compatible store). Credentials and
+// region come from the standard AWS env vars / shared config, picked up by
+// config.LoadDefaultConfig.
+type s3Writer struct{}
+
+func init() {
+	Register("s3", &s3Writer{})
+}
+
+func (w *s3Writer) client(ctx context.Context) (*s3.Client, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s3.NewFromConfig(cfg), nil
+}
+
+// s3PipeWriter streams the bytes written to it into a PutObject upload that
+// starts in Create; Close ends the stream and waits for the upload's result,
+// so callers can treat it like any other io.WriteCloser.
+type s3PipeWriter struct {
+	pw   *io.PipeWriter
+	done chan error
+}
+
+func (p *s3PipeWriter) Write(b []byte) (int, error) { return p.pw.Write(b) }
+
+func (p *s3PipeWriter) Close() error {
+	if err := p.pw.Close(); err != nil {
+		return err
+	}
+	return <-p.done
+}
+
+func (w *s3Writer) Create(ctx context.Context, uri string) (io.WriteCloser, error) {
+	bucket, key, err := splitBucketObject(uri)
+	if err != nil {
+		return nil, err
+	}
+	client, err := w.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	out := &s3PipeWriter{pw: pw, done: make(chan error, 1)}
+	go func() {
+		_, err := client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+			Body:   pr,
+		})
+		pr.CloseWithError(err)
+		out.done <- err
+	}()
+	return out, nil
+}
+
+func (w *s3Writer) Exists(ctx context.Context, uri string) (bool, error) {
+	_, err := w.Stat(ctx, uri)
+	if err != nil {
+		var nf *types.NotFound
+		if errors.As(err, &nf) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (w *s3Writer) Stat(ctx context.Context, uri string) (Info, error) {
+	bucket, key, err := splitBucketObject(uri)
+	if err != nil {
+		return Info{}, err
+	}
+	client, err := w.client(ctx)
+	if err != nil {
+		return Info{}, err
+	}
+	out, err := client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	if err != nil {
+		return Info{}, err
+	}
+	return Info{Size: aws.ToInt64(out.ContentLength)}, nil
+}
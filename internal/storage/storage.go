@@ -0,0 +1,97 @@
+// Package storage abstracts where generated images are written, so a single
+// `-o` destination can point at the local filesystem or an object store
+// without the caller needing to care which.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// Info is the subset of object metadata callers need from Stat.
+type Info struct {
+	Size int64
+}
+
+// Writer is implemented by each supported backend (file, s3, gs, ...).
+type Writer interface {
+	// Create opens uri for writing, truncating any existing object.
+	Create(ctx context.Context, uri string) (io.WriteCloser, error)
+	// Exists reports whether uri already refers to an object.
+	Exists(ctx context.Context, uri string) (bool, error)
+	// Stat returns metadata about an existing object.
+	Stat(ctx context.Context, uri string) (Info, error)
+}
+
+var backends = map[string]Writer{}
+
+// Register associates scheme (e.g. "s3") with a Writer implementation.
+// Backends call this from an init() func so selecting a scheme is just a
+// matter of importing the package for side effects.
+func Register(scheme string, w Writer) {
+	backends[scheme] = w
+}
+
+func init() {
+	Register("file", &fileWriter{})
+}
+
+// scheme extracts the URI scheme, defaulting to "file" for bare paths like
+// "out.png" so local paths need no prefix.
+func scheme(uri string) string {
+	if i := strings.Index(uri, "://"); i != -1 {
+		return uri[:i]
+	}
+	return "file"
+}
+
+func resolve(uri string) (Writer, error) {
+	w, ok := backends[scheme(uri)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported storage scheme %q (parsed from %q)", scheme(uri), uri)
+	}
+	return w, nil
+}
+
+// Create opens uri for writing via the backend matching its scheme.
+func Create(ctx context.Context, uri string) (io.WriteCloser, error) {
+	w, err := resolve(uri)
+	if err != nil {
+		return nil, err
+	}
+	return w.Create(ctx, uri)
+}
+
+// Exists reports whether uri already refers to an object.
+func Exists(ctx context.Context, uri string) (bool, error) {
+	w, err := resolve(uri)
+	if err != nil {
+		return false, err
+	}
+	return w.Exists(ctx, uri)
+}
+
+// Stat returns metadata about an existing object at uri.
+func Stat(ctx context.Context, uri string) (Info, error) {
+	w, err := resolve(uri)
+	if err != nil {
+		return Info{}, err
+	}
+	return w.Stat(ctx, uri)
+}
+
+// splitBucketObject splits a "scheme://bucket/object/path" URI into its
+// bucket and object components, as used by the s3 and gs backends.
+func splitBucketObject(uri string) (bucket, object string, err error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid URI %q: %w", uri, err)
+	}
+	if u.Host == "" {
+		return "", "", fmt.Errorf("invalid URI %q: missing bucket", uri)
+	}
+	return u.Host, strings.TrimPrefix(u.Path, "/"), nil
+}